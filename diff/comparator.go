@@ -0,0 +1,60 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Comparator reports whether the local and remote values of a project field
+// are equal. Values are already normalized to NFC, and for whitespace when
+// Options.NormalizeWhitespace is set.
+type Comparator func(local, remote string) bool
+
+// ComparableFields are the project fields a Comparator can be registered
+// for.
+var ComparableFields = []string{"title", "description", "url", "kind"}
+
+var builtinComparators = map[string]Comparator{
+	"url":  equalURLs,
+	"kind": equalKinds,
+}
+
+var (
+	comparatorsMu sync.RWMutex
+	comparators   = map[string]Comparator{}
+)
+
+// RegisterComparator sets the comparator for one of the ComparableFields,
+// replacing the built-in one. A nil comparator restores the built-in
+// behaviour. Fields without a comparator are compared for exact equality.
+func RegisterComparator(field string, c Comparator) error {
+	if !slices.Contains(ComparableFields, field) {
+		return fmt.Errorf("registering comparator: unknown field %q, must be one of %v", field, ComparableFields)
+	}
+
+	comparatorsMu.Lock()
+	defer comparatorsMu.Unlock()
+
+	if c == nil {
+		delete(comparators, field)
+		return nil
+	}
+	comparators[field] = c
+
+	return nil
+}
+
+func comparatorFor(field string) Comparator {
+	comparatorsMu.RLock()
+	defer comparatorsMu.RUnlock()
+
+	if c, ok := comparators[field]; ok {
+		return c
+	}
+	if c, ok := builtinComparators[field]; ok {
+		return c
+	}
+
+	return func(local, remote string) bool { return local == remote }
+}
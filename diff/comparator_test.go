@@ -0,0 +1,57 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/release-sdk/obs"
+)
+
+func TestRegisterComparator(t *testing.T) {
+	local := project(obs.Project{Title: "Kubernetes", URL: "https://kubernetes.io"})
+	remote := &obs.Project{Title: "KUBERNETES", URL: "https://kubernetes.io/"}
+
+	changed := func() []string {
+		var fields []string
+		for _, c := range compareProjects(local, remote, Options{}) {
+			fields = append(fields, c.Field)
+		}
+		return fields
+	}
+
+	if got := strings.Join(changed(), ","); got != "title" {
+		t.Fatalf("changed fields before registering = %q, want %q", got, "title")
+	}
+
+	// Override a field without a built-in comparator.
+	if err := RegisterComparator("title", strings.EqualFold); err != nil {
+		t.Fatalf("RegisterComparator(title) error = %v", err)
+	}
+	if got := changed(); len(got) != 0 {
+		t.Errorf("changed fields with case-insensitive title = %v, want none", got)
+	}
+
+	// Override a built-in comparator.
+	if err := RegisterComparator("url", func(l, r string) bool { return l == r }); err != nil {
+		t.Fatalf("RegisterComparator(url) error = %v", err)
+	}
+	if got := strings.Join(changed(), ","); got != "url" {
+		t.Errorf("changed fields with exact url = %q, want %q", got, "url")
+	}
+
+	// A nil comparator restores the built-in behaviour.
+	for _, field := range []string{"title", "url"} {
+		if err := RegisterComparator(field, nil); err != nil {
+			t.Fatalf("RegisterComparator(%s, nil) error = %v", field, err)
+		}
+	}
+	if got := strings.Join(changed(), ","); got != "title" {
+		t.Errorf("changed fields after reset = %q, want %q", got, "title")
+	}
+
+	for _, field := range []string{"titel", "persons", ""} {
+		if err := RegisterComparator(field, strings.EqualFold); err == nil {
+			t.Errorf("RegisterComparator(%q) error = nil, want an error", field)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+// Package diff compares manifest projects against the project meta stored
+// in OBS.
+package diff
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+	"sigs.k8s.io/obscli/types"
+	"sigs.k8s.io/release-sdk/obs"
+)
+
+// ChangeKind describes how a field differs.
+type ChangeKind string
+
+const (
+	// ChangeAdded means the field is only set locally.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved means the field is only set remotely.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeModified means both sides set the field to different values.
+	ChangeModified ChangeKind = "modified"
+)
+
+// FieldChange is a single field whose local and remote values differ.
+type FieldChange struct {
+	Field  string     `json:"field"`
+	Kind   ChangeKind `json:"kind"`
+	Local  string     `json:"local,omitempty"`
+	Remote string     `json:"remote,omitempty"`
+}
+
+// newChange records a change to a field for which an empty value means
+// unset.
+func newChange(field, local, remote string) FieldChange {
+	kind := ChangeModified
+	switch {
+	case remote == "":
+		kind = ChangeAdded
+	case local == "":
+		kind = ChangeRemoved
+	}
+
+	return newChangeOfKind(field, kind, local, remote)
+}
+
+func newChangeOfKind(field string, kind ChangeKind, local, remote string) FieldChange {
+	return FieldChange{Field: field, Kind: kind, Local: local, Remote: remote}
+}
+
+// ProjectDiff holds the differences between a manifest project and its
+// remote counterpart.
+type ProjectDiff struct {
+	Name string `json:"name"`
+	// Missing is set when the project does not exist on OBS.
+	Missing bool `json:"missing,omitempty"`
+	// Removed is set when the project only exists on the remote side.
+	Removed bool          `json:"removed,omitempty"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// Changed reports whether the project differs between the two sides: it is
+// missing or removed on one side, or any field changed. Against OBS this
+// means reconciling would modify the project.
+func (d ProjectDiff) Changed() bool {
+	return d.Missing || d.Removed || len(d.Changes) > 0
+}
+
+// Options tunes how projects are compared.
+type Options struct {
+	// OnlyManagedRepositories ignores remote repositories that are not
+	// named in the manifest, so repositories owned by others are kept.
+	OnlyManagedRepositories bool
+	// NormalizeWhitespace ignores leading and trailing whitespace, trailing
+	// whitespace on each line and line ending differences in string fields.
+	NormalizeWhitespace bool
+	// IgnoreRepositories matches the names of remote repositories, such as
+	// defaults injected by OBS, that are ignored unless the manifest
+	// declares them.
+	IgnoreRepositories *regexp.Regexp
+}
+
+// Diff compares a manifest project with the remote project meta. A nil
+// remote means the project does not exist yet.
+func Diff(local types.Project, remote *obs.Project) ProjectDiff {
+	return DiffWithOptions(local, remote, Options{})
+}
+
+// DiffWithOptions is like Diff but compares according to opts.
+func DiffWithOptions(local types.Project, remote *obs.Project, opts Options) ProjectDiff {
+	d := ProjectDiff{Name: local.Name}
+	if remote == nil {
+		d.Missing = true
+		return d
+	}
+
+	remoteRepos := remote.Repositories
+	if opts.OnlyManagedRepositories {
+		remoteRepos = managedRepositories(local.Repositories, remoteRepos)
+	}
+	if opts.IgnoreRepositories != nil {
+		remoteRepos = withoutIgnoredRepositories(local.Repositories, remoteRepos, opts.IgnoreRepositories)
+	}
+
+	d.Changes = append(d.Changes, compareProjects(local, remote, opts)...)
+	d.Changes = append(d.Changes, compareFlags(local, remote)...)
+	d.Changes = append(d.Changes, comparePersons(local.Persons, remote.Persons)...)
+	d.Changes = append(d.Changes, compareRepositories(local.Repositories, remoteRepos)...)
+
+	return d
+}
+
+func compareProjects(local types.Project, remote *obs.Project, opts Options) []FieldChange {
+	var changes []FieldChange
+
+	// A project always has a kind, an empty one is standard, so changes to
+	// it are modifications rather than additions or removals.
+	fields := []struct {
+		name          string
+		local, remote string
+		alwaysSet     bool
+	}{
+		{"title", local.Title, remote.Title, false},
+		{"description", local.Description, remote.Description, false},
+		{"url", local.URL, remote.URL, false},
+		{"kind", local.Kind, remote.Kind, true},
+	}
+	for _, f := range fields {
+		// OBS may hand back text in a different Unicode normal form than
+		// the manifest uses, so compare both sides in NFC.
+		l, r := norm.NFC.String(f.local), norm.NFC.String(f.remote)
+		if opts.NormalizeWhitespace {
+			l, r = normalizeWhitespace(l), normalizeWhitespace(r)
+		}
+		if comparatorFor(f.name)(l, r) {
+			continue
+		}
+		if f.alwaysSet {
+			changes = append(changes, newChangeOfKind(f.name, ChangeModified, f.local, f.remote))
+		} else {
+			changes = append(changes, newChange(f.name, f.local, f.remote))
+		}
+	}
+
+	return changes
+}
+
+// compareFlags compares the project-wide build and publish flags. In the
+// manifest a flag is tri-state: leaving it out means it is not managed and
+// never reported, "build: {}" enables it and "build: {disable: {}}"
+// disables it. OBS omitting the flag counts as enabled.
+func compareFlags(local types.Project, remote *obs.Project) []FieldChange {
+	var changes []FieldChange
+
+	if local.Build != nil {
+		l, r := flagState(local.Build.Disable), flagState(nil)
+		if remote.Build != nil {
+			r = flagState(remote.Build.Disable)
+		}
+		if l != r {
+			changes = append(changes, newChange("build", l, r))
+		}
+	}
+
+	if local.Publish != nil {
+		l, r := flagState(local.Publish.Disable), flagState(nil)
+		if remote.Publish != nil {
+			r = flagState(remote.Publish.Disable)
+		}
+		if l != r {
+			changes = append(changes, newChange("publish", l, r))
+		}
+	}
+
+	return changes
+}
+
+func flagState(disable *obs.Disabled) string {
+	if disable != nil {
+		return "disabled"
+	}
+	return "enabled"
+}
+
+// equalKinds treats an empty kind as standard, since OBS omits the kind
+// attribute from the meta of standard projects.
+func equalKinds(local, remote string) bool {
+	kind := func(k string) string {
+		if k == "" {
+			return "standard"
+		}
+		return k
+	}
+
+	return kind(local) == kind(remote)
+}
+
+func equalURLs(local, remote string) bool {
+	return normalizeURL(local) == normalizeURL(remote)
+}
+
+// normalizeURL lowercases the scheme and host and drops a trailing slash,
+// which OBS and manifests do not use consistently.
+func normalizeURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || !u.IsAbs() {
+		return strings.TrimSuffix(s, "/")
+	}
+
+	// Trim the escaped path so that an escaped slash such as %2F is kept
+	// distinct from a path separator.
+	escaped := strings.TrimSuffix(u.EscapedPath(), "/")
+	path, err := url.PathUnescape(escaped)
+	if err != nil {
+		return strings.TrimSuffix(s, "/")
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = path
+	u.RawPath = escaped
+
+	return u.String()
+}
+
+func normalizeWhitespace(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func comparePersons(local, remote []obs.Person) []FieldChange {
+	l, r := personKeys(local), personKeys(remote)
+	if slices.Equal(l, r) {
+		return nil
+	}
+
+	return []FieldChange{newChange("persons", strings.Join(l, ","), strings.Join(r, ","))}
+}
+
+func personKeys(persons []obs.Person) []string {
+	keys := make([]string, 0, len(persons))
+	for _, p := range persons {
+		keys = append(keys, fmt.Sprintf("%s:%s", p.Role, p.UserID))
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+func compareRepositories(local, remote []obs.Repository) []FieldChange {
+	var changes []FieldChange
+
+	remoteByName := make(map[string]obs.Repository, len(remote))
+	for _, repo := range remote {
+		remoteByName[repo.Repository] = repo
+	}
+
+	l, r := repositoryNames(local), repositoryNames(remote)
+	if !slices.Equal(l, r) {
+		changes = append(changes, newChange("repositories", strings.Join(l, ","), strings.Join(r, ",")))
+	}
+
+	for _, repo := range local {
+		remoteRepo, ok := remoteByName[repo.Repository]
+		if !ok {
+			continue
+		}
+
+		field := fmt.Sprintf("repositories[%s]", repo.Repository)
+		changes = append(changes, compareArchitectures(field, repo.Architectures, remoteRepo.Architectures)...)
+
+		lp, rp := pathKeys(repo.Paths), pathKeys(remoteRepo.Paths)
+		if !slices.Equal(lp, rp) {
+			changes = append(changes, newChange(field+".paths", strings.Join(lp, ","), strings.Join(rp, ",")))
+		}
+
+		lt, rt := releaseTargetKeys(repo.ReleaseTargets), releaseTargetKeys(remoteRepo.ReleaseTargets)
+		if !slices.Equal(lt, rt) {
+			changes = append(changes, newChange(field+".releaseTargets", strings.Join(lt, ","), strings.Join(rt, ",")))
+		}
+	}
+
+	return changes
+}
+
+// managedRepositories returns the remote repositories that are also
+// declared locally.
+func managedRepositories(local, remote []obs.Repository) []obs.Repository {
+	names := repositoryNames(local)
+
+	var managed []obs.Repository
+	for _, repo := range remote {
+		if _, found := slices.BinarySearch(names, repo.Repository); found {
+			managed = append(managed, repo)
+		}
+	}
+
+	return managed
+}
+
+// withoutIgnoredRepositories drops the remote repositories matching ignore
+// that are not declared locally.
+func withoutIgnoredRepositories(local, remote []obs.Repository, ignore *regexp.Regexp) []obs.Repository {
+	names := repositoryNames(local)
+
+	var kept []obs.Repository
+	for _, repo := range remote {
+		if _, found := slices.BinarySearch(names, repo.Repository); found || !ignore.MatchString(repo.Repository) {
+			kept = append(kept, repo)
+		}
+	}
+
+	return kept
+}
+
+// MergeRepositories returns the local repositories followed by any remote
+// repositories the manifest does not declare. Updating a project with the
+// result leaves repositories managed elsewhere in place.
+func MergeRepositories(local, remote []obs.Repository) []obs.Repository {
+	names := repositoryNames(local)
+
+	merged := slices.Clone(local)
+	for _, repo := range remote {
+		if _, found := slices.BinarySearch(names, repo.Repository); !found {
+			merged = append(merged, repo)
+		}
+	}
+
+	return merged
+}
+
+func repositoryNames(repos []obs.Repository) []string {
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, repo.Repository)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// pathKeys keeps the order of the paths, since OBS resolves dependencies in
+// the order they are listed.
+func pathKeys(paths []obs.RepositoryPath) []string {
+	keys := make([]string, 0, len(paths))
+	for _, p := range paths {
+		keys = append(keys, p.Project+"/"+p.Repository)
+	}
+
+	return keys
+}
+
+func releaseTargetKeys(targets []obs.ReleaseTarget) []string {
+	keys := make([]string, 0, len(targets))
+	for _, t := range targets {
+		keys = append(keys, fmt.Sprintf("%s/%s:%s", t.ProjectName, t.Repository, t.Trigger))
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+func compareArchitectures(field string, local, remote []string) []FieldChange {
+	l, r := slices.Clone(local), slices.Clone(remote)
+	slices.Sort(l)
+	slices.Sort(r)
+	if slices.Equal(l, r) {
+		return nil
+	}
+
+	return []FieldChange{newChange(field+".architectures", strings.Join(l, ","), strings.Join(r, ","))}
+}
@@ -0,0 +1,543 @@
+package diff
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"sigs.k8s.io/obscli/types"
+	"sigs.k8s.io/release-sdk/obs"
+)
+
+func project(p obs.Project) types.Project {
+	return types.Project{Project: p}
+}
+
+func repo(name string, archs ...string) obs.Repository {
+	return obs.Repository{Repository: name, Architectures: archs}
+}
+
+func TestCompareProjects(t *testing.T) {
+	base := obs.Project{
+		Name:        "isv:kubernetes",
+		Title:       "Kubernetes",
+		Description: "Kubernetes packages",
+		URL:         "https://kubernetes.io",
+	}
+
+	tests := []struct {
+		name   string
+		local  obs.Project
+		remote obs.Project
+		opts   Options
+		want   []FieldChange
+	}{
+		{
+			name:   "equal",
+			local:  base,
+			remote: base,
+		},
+		{
+			name:   "modified title",
+			local:  obs.Project{Title: "Kubernetes"},
+			remote: obs.Project{Title: "Kubernetes core"},
+			want:   []FieldChange{{Field: "title", Kind: ChangeModified, Local: "Kubernetes", Remote: "Kubernetes core"}},
+		},
+		{
+			name:   "added description",
+			local:  obs.Project{Description: "Kubernetes packages"},
+			remote: obs.Project{},
+			want:   []FieldChange{{Field: "description", Kind: ChangeAdded, Local: "Kubernetes packages"}},
+		},
+		{
+			name:   "removed url",
+			local:  obs.Project{},
+			remote: obs.Project{URL: "https://kubernetes.io"},
+			want:   []FieldChange{{Field: "url", Kind: ChangeRemoved, Remote: "https://kubernetes.io"}},
+		},
+		{
+			name:   "NFC and NFD titles are equal",
+			local:  obs.Project{Title: "Caf\u00e9"},
+			remote: obs.Project{Title: "Cafe\u0301"},
+		},
+		{
+			name:   "whitespace differences are drift by default",
+			local:  obs.Project{Description: "line one\nline two"},
+			remote: obs.Project{Description: "line one  \r\nline two\n"},
+			want: []FieldChange{{
+				Field: "description", Kind: ChangeModified,
+				Local: "line one\nline two", Remote: "line one  \r\nline two\n",
+			}},
+		},
+		{
+			name:   "whitespace differences are ignored when normalizing",
+			local:  obs.Project{Description: "line one\nline two"},
+			remote: obs.Project{Description: "line one  \r\nline two\n"},
+			opts:   Options{NormalizeWhitespace: true},
+		},
+		{
+			name:   "url trailing slash and host case are ignored",
+			local:  obs.Project{URL: "https://Kubernetes.io/docs"},
+			remote: obs.Project{URL: "https://kubernetes.io/docs/"},
+		},
+		{
+			name:   "escaped slash in url path is drift",
+			local:  obs.Project{URL: "https://x.org/a%2Fb"},
+			remote: obs.Project{URL: "https://x.org/a/b/"},
+			want: []FieldChange{{
+				Field: "url", Kind: ChangeModified,
+				Local: "https://x.org/a%2Fb", Remote: "https://x.org/a/b/",
+			}},
+		},
+		{
+			name:   "escaped url path keeps trailing slash normalization",
+			local:  obs.Project{URL: "https://x.org/a%2Fb/"},
+			remote: obs.Project{URL: "https://X.org/a%2Fb"},
+		},
+		{
+			name:   "different url paths are drift",
+			local:  obs.Project{URL: "https://kubernetes.io/docs"},
+			remote: obs.Project{URL: "https://kubernetes.io/blog"},
+			want: []FieldChange{{
+				Field: "url", Kind: ChangeModified,
+				Local: "https://kubernetes.io/docs", Remote: "https://kubernetes.io/blog",
+			}},
+		},
+		{
+			name:   "standard kind equals omitted kind",
+			local:  obs.Project{Kind: "standard"},
+			remote: obs.Project{},
+		},
+		{
+			name:   "kind changed from omitted standard",
+			local:  obs.Project{Kind: "maintenance"},
+			remote: obs.Project{},
+			want:   []FieldChange{{Field: "kind", Kind: ChangeModified, Local: "maintenance"}},
+		},
+		{
+			name:   "kind changed back to standard",
+			local:  obs.Project{},
+			remote: obs.Project{Kind: "maintenance"},
+			want:   []FieldChange{{Field: "kind", Kind: ChangeModified, Remote: "maintenance"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := compareProjects(project(tc.local), &tc.remote, tc.opts)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("compareProjects() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestComparePersons(t *testing.T) {
+	alice := obs.Person{UserID: "alice", Role: obs.PersonRoleMaintainer}
+	bob := obs.Person{UserID: "bob", Role: obs.PersonRoleBugOwner}
+	bobMaintainer := obs.Person{UserID: "bob", Role: obs.PersonRoleMaintainer}
+
+	tests := []struct {
+		name   string
+		local  []obs.Person
+		remote []obs.Person
+		want   []FieldChange
+	}{
+		{
+			name:   "equal",
+			local:  []obs.Person{alice, bob},
+			remote: []obs.Person{alice, bob},
+		},
+		{
+			name:   "reordered",
+			local:  []obs.Person{alice, bob},
+			remote: []obs.Person{bob, alice},
+		},
+		{
+			name:   "added",
+			local:  []obs.Person{alice, bob},
+			remote: []obs.Person{alice},
+			want: []FieldChange{{
+				Field: "persons", Kind: ChangeModified,
+				Local: "bugowner:bob,maintainer:alice", Remote: "maintainer:alice",
+			}},
+		},
+		{
+			name:   "modified role",
+			local:  []obs.Person{alice, bobMaintainer},
+			remote: []obs.Person{alice, bob},
+			want: []FieldChange{{
+				Field: "persons", Kind: ChangeModified,
+				Local: "maintainer:alice,maintainer:bob", Remote: "bugowner:bob,maintainer:alice",
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := comparePersons(tc.local, tc.remote)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("comparePersons() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiffRemovedPerson(t *testing.T) {
+	alice := obs.Person{UserID: "alice", Role: obs.PersonRoleMaintainer}
+	bob := obs.Person{UserID: "bob", Role: obs.PersonRoleMaintainer}
+
+	// Bob was dropped from the manifest but is still on OBS, so the diff
+	// reports a persons change listing the manifest and the OBS persons.
+	local := project(obs.Project{Name: "isv:kubernetes", Persons: []obs.Person{alice}})
+	remote := &obs.Project{Name: "isv:kubernetes", Persons: []obs.Person{bob, alice}}
+
+	want := ProjectDiff{
+		Name: "isv:kubernetes",
+		Changes: []FieldChange{{
+			Field: "persons", Kind: ChangeModified,
+			Local: "maintainer:alice", Remote: "maintainer:alice,maintainer:bob",
+		}},
+	}
+	if got := Diff(local, remote); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+
+	// Dropping every person is reported as a removal.
+	local.Persons = nil
+	want.Changes = []FieldChange{{
+		Field: "persons", Kind: ChangeRemoved,
+		Remote: "maintainer:alice,maintainer:bob",
+	}}
+	if got := Diff(local, remote); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUpdateReplacesPersons(t *testing.T) {
+	alice := obs.Person{UserID: "alice", Role: obs.PersonRoleMaintainer}
+
+	var sent obs.Project
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/source/isv:kubernetes/_meta" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	// Bob is on OBS but was dropped from the manifest. The meta PUT for the
+	// manifest project must list only alice, since OBS replaces the person
+	// list with the one it is sent.
+	local := project(obs.Project{Name: "isv:kubernetes", Persons: []obs.Person{alice}})
+	client := obs.New(&obs.Options{APIURL: server.URL})
+	if err := client.CreateUpdateProject(context.Background(), &local.Project); err != nil {
+		t.Fatalf("CreateUpdateProject() error = %v", err)
+	}
+
+	if want := []obs.Person{alice}; !reflect.DeepEqual(sent.Persons, want) {
+		t.Errorf("PUT persons = %+v, want %+v", sent.Persons, want)
+	}
+}
+
+func TestCompareRepositories(t *testing.T) {
+	withPaths := func(r obs.Repository, paths ...obs.RepositoryPath) obs.Repository {
+		r.Paths = paths
+		return r
+	}
+	withTargets := func(r obs.Repository, targets ...obs.ReleaseTarget) obs.Repository {
+		r.ReleaseTargets = targets
+		return r
+	}
+	tumbleweed := obs.RepositoryPath{Project: "openSUSE:Factory", Repository: "snapshot"}
+	debian := obs.RepositoryPath{Project: "Debian:12", Repository: "standard"}
+	release := obs.ReleaseTarget{ProjectName: "isv:kubernetes:release", Repository: "deb", Trigger: "manual"}
+	staging := obs.ReleaseTarget{ProjectName: "isv:kubernetes:staging", Repository: "deb", Trigger: "manual"}
+
+	tests := []struct {
+		name   string
+		local  []obs.Repository
+		remote []obs.Repository
+		want   []FieldChange
+	}{
+		{
+			name:   "equal",
+			local:  []obs.Repository{repo("deb", "x86_64"), repo("rpm", "x86_64")},
+			remote: []obs.Repository{repo("deb", "x86_64"), repo("rpm", "x86_64")},
+		},
+		{
+			name:   "reordered",
+			local:  []obs.Repository{repo("deb", "x86_64"), repo("rpm", "x86_64")},
+			remote: []obs.Repository{repo("rpm", "x86_64"), repo("deb", "x86_64")},
+		},
+		{
+			name:   "added",
+			local:  []obs.Repository{repo("deb", "x86_64"), repo("rpm", "x86_64")},
+			remote: []obs.Repository{repo("deb", "x86_64")},
+			want:   []FieldChange{{Field: "repositories", Kind: ChangeModified, Local: "deb,rpm", Remote: "deb"}},
+		},
+		{
+			name:   "removed",
+			local:  nil,
+			remote: []obs.Repository{repo("deb", "x86_64")},
+			want:   []FieldChange{{Field: "repositories", Kind: ChangeRemoved, Remote: "deb"}},
+		},
+		{
+			name:   "modified architectures",
+			local:  []obs.Repository{repo("deb", "x86_64", "aarch64")},
+			remote: []obs.Repository{repo("deb", "x86_64")},
+			want: []FieldChange{{
+				Field: "repositories[deb].architectures", Kind: ChangeModified,
+				Local: "aarch64,x86_64", Remote: "x86_64",
+			}},
+		},
+		{
+			name:   "reordered paths are drift",
+			local:  []obs.Repository{withPaths(repo("deb"), tumbleweed, debian)},
+			remote: []obs.Repository{withPaths(repo("deb"), debian, tumbleweed)},
+			want: []FieldChange{{
+				Field: "repositories[deb].paths", Kind: ChangeModified,
+				Local: "openSUSE:Factory/snapshot,Debian:12/standard", Remote: "Debian:12/standard,openSUSE:Factory/snapshot",
+			}},
+		},
+		{
+			name:   "reordered release targets",
+			local:  []obs.Repository{withTargets(repo("deb"), release, staging)},
+			remote: []obs.Repository{withTargets(repo("deb"), staging, release)},
+		},
+		{
+			name:   "release targets removed from the manifest",
+			local:  []obs.Repository{repo("deb")},
+			remote: []obs.Repository{withTargets(repo("deb"), release)},
+			want: []FieldChange{{
+				Field: "repositories[deb].releaseTargets", Kind: ChangeRemoved,
+				Remote: "isv:kubernetes:release/deb:manual",
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := compareRepositories(tc.local, tc.remote)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("compareRepositories() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCompareArchitectures(t *testing.T) {
+	tests := []struct {
+		name   string
+		local  []string
+		remote []string
+		want   []FieldChange
+	}{
+		{
+			name:   "equal",
+			local:  []string{"x86_64", "aarch64"},
+			remote: []string{"x86_64", "aarch64"},
+		},
+		{
+			name:   "reordered",
+			local:  []string{"x86_64", "aarch64"},
+			remote: []string{"aarch64", "x86_64"},
+		},
+		{
+			name:   "added",
+			local:  []string{"x86_64"},
+			remote: nil,
+			want:   []FieldChange{{Field: "deb.architectures", Kind: ChangeAdded, Local: "x86_64"}},
+		},
+		{
+			name:   "removed",
+			local:  nil,
+			remote: []string{"x86_64"},
+			want:   []FieldChange{{Field: "deb.architectures", Kind: ChangeRemoved, Remote: "x86_64"}},
+		},
+		{
+			name:   "modified",
+			local:  []string{"x86_64", "ppc64le"},
+			remote: []string{"x86_64", "s390x"},
+			want: []FieldChange{{
+				Field: "deb.architectures", Kind: ChangeModified,
+				Local: "ppc64le,x86_64", Remote: "s390x,x86_64",
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			local := append([]string(nil), tc.local...)
+			got := compareArchitectures("deb", tc.local, tc.remote)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("compareArchitectures() = %+v, want %+v", got, tc.want)
+			}
+			if !reflect.DeepEqual(tc.local, local) {
+				t.Errorf("compareArchitectures() modified its input: %v", tc.local)
+			}
+		})
+	}
+}
+
+func TestCompareFlags(t *testing.T) {
+	enabled := &obs.Build{}
+	disabled := &obs.Build{Disable: &obs.Disabled{}}
+
+	tests := []struct {
+		name   string
+		local  obs.Project
+		remote obs.Project
+		want   []FieldChange
+	}{
+		{
+			name:   "unset flags are not managed",
+			local:  obs.Project{},
+			remote: obs.Project{Build: disabled, Publish: &obs.Publish{Disable: &obs.Disabled{}}},
+		},
+		{
+			name:   "enabled equals omitted",
+			local:  obs.Project{Build: enabled, Publish: &obs.Publish{}},
+			remote: obs.Project{},
+		},
+		{
+			name:   "disable build",
+			local:  obs.Project{Build: disabled},
+			remote: obs.Project{Build: enabled},
+			want:   []FieldChange{{Field: "build", Kind: ChangeModified, Local: "disabled", Remote: "enabled"}},
+		},
+		{
+			name:   "enable publish",
+			local:  obs.Project{Publish: &obs.Publish{}},
+			remote: obs.Project{Publish: &obs.Publish{Disable: &obs.Disabled{}}},
+			want:   []FieldChange{{Field: "publish", Kind: ChangeModified, Local: "enabled", Remote: "disabled"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := compareFlags(project(tc.local), &tc.remote)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("compareFlags() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiffWithOptions(t *testing.T) {
+	local := project(obs.Project{
+		Name:         "isv:kubernetes",
+		Repositories: []obs.Repository{repo("deb", "x86_64")},
+	})
+	remote := &obs.Project{
+		Name: "isv:kubernetes",
+		Repositories: []obs.Repository{
+			repo("deb", "x86_64"),
+			repo("openSUSE_Tumbleweed", "x86_64"),
+		},
+	}
+	repositoriesChange := []FieldChange{{
+		Field: "repositories", Kind: ChangeModified,
+		Local: "deb", Remote: "deb,openSUSE_Tumbleweed",
+	}}
+
+	tests := []struct {
+		name   string
+		local  types.Project
+		remote *obs.Project
+		opts   Options
+		want   ProjectDiff
+	}{
+		{
+			name:   "missing project",
+			local:  local,
+			remote: nil,
+			want:   ProjectDiff{Name: "isv:kubernetes", Missing: true},
+		},
+		{
+			name:   "extra remote repository is drift",
+			local:  local,
+			remote: remote,
+			want:   ProjectDiff{Name: "isv:kubernetes", Changes: repositoriesChange},
+		},
+		{
+			name:   "only managed repositories",
+			local:  local,
+			remote: remote,
+			opts:   Options{OnlyManagedRepositories: true},
+			want:   ProjectDiff{Name: "isv:kubernetes"},
+		},
+		{
+			name:   "ignored repository",
+			local:  local,
+			remote: remote,
+			opts:   Options{IgnoreRepositories: regexp.MustCompile(`^openSUSE_`)},
+			want:   ProjectDiff{Name: "isv:kubernetes"},
+		},
+		{
+			name:   "ignore pattern does not match",
+			local:  local,
+			remote: remote,
+			opts:   Options{IgnoreRepositories: regexp.MustCompile(`^images$`)},
+			want:   ProjectDiff{Name: "isv:kubernetes", Changes: repositoriesChange},
+		},
+		{
+			name: "ignored repository declared in the manifest is compared",
+			local: project(obs.Project{
+				Name:         "isv:kubernetes",
+				Repositories: []obs.Repository{repo("deb", "x86_64"), repo("openSUSE_Tumbleweed", "aarch64")},
+			}),
+			remote: remote,
+			opts:   Options{IgnoreRepositories: regexp.MustCompile(`^openSUSE_`)},
+			want: ProjectDiff{Name: "isv:kubernetes", Changes: []FieldChange{{
+				Field: "repositories[openSUSE_Tumbleweed].architectures", Kind: ChangeModified,
+				Local: "aarch64", Remote: "x86_64",
+			}}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := DiffWithOptions(tc.local, tc.remote, tc.opts)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("DiffWithOptions() = %+v, want %+v", got, tc.want)
+			}
+			if got.Changed() != (tc.want.Missing || len(tc.want.Changes) > 0) {
+				t.Errorf("Changed() = %v", got.Changed())
+			}
+		})
+	}
+}
+
+func TestMergeRepositories(t *testing.T) {
+	local := []obs.Repository{repo("deb", "x86_64", "aarch64")}
+	remote := []obs.Repository{repo("deb", "x86_64"), repo("images", "x86_64")}
+
+	want := []obs.Repository{repo("deb", "x86_64", "aarch64"), repo("images", "x86_64")}
+	if got := MergeRepositories(local, remote); !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeRepositories() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewChange(t *testing.T) {
+	tests := []struct {
+		local, remote string
+		want          ChangeKind
+	}{
+		{"a", "", ChangeAdded},
+		{"", "a", ChangeRemoved},
+		{"a", "b", ChangeModified},
+	}
+
+	for _, tc := range tests {
+		if got := newChange("title", tc.local, tc.remote).Kind; got != tc.want {
+			t.Errorf("newChange(%q, %q).Kind = %q, want %q", tc.local, tc.remote, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/obscli/types"
+	"sigs.k8s.io/release-sdk/obs"
+)
+
+// Manifests compares two manifests offline, treating old as the remote
+// side. Projects and subprojects are matched by name. Projects only in
+// current are reported as Missing and projects only in old as Removed.
+// Besides the project meta, fields that only exist in the manifest, such as
+// packages and the root project, are compared too.
+func Manifests(old, current types.Projects, opts Options) []ProjectDiff {
+	oldByName := make(map[string]types.Project)
+	for _, p := range flatten(old.Projects) {
+		oldByName[p.Name] = p
+	}
+
+	var diffs []ProjectDiff
+	seen := make(map[string]bool)
+	for _, p := range flatten(current.Projects) {
+		seen[p.Name] = true
+
+		o, ok := oldByName[p.Name]
+		if !ok {
+			diffs = append(diffs, DiffWithOptions(p, nil, opts))
+			continue
+		}
+		d := DiffWithOptions(p, &o.Project, opts)
+		// compareFlags treats a flag the local side leaves unset as
+		// unmanaged, which would hide a flag dropped from the manifest.
+		// Between two manifests, whether a flag is set is itself compared.
+		d.Changes = slices.DeleteFunc(d.Changes, func(c FieldChange) bool {
+			return c.Field == "build" || c.Field == "publish"
+		})
+		d.Changes = append(d.Changes, compareManifestFields(p, o)...)
+		diffs = append(diffs, d)
+	}
+
+	var removed []string
+	for name := range oldByName {
+		if !seen[name] {
+			removed = append(removed, name)
+		}
+	}
+	slices.Sort(removed)
+	for _, name := range removed {
+		diffs = append(diffs, ProjectDiff{Name: name, Removed: true})
+	}
+
+	return diffs
+}
+
+func flatten(projects []types.Project) []types.Project {
+	var all []types.Project
+	for _, p := range projects {
+		all = append(all, p)
+		all = append(all, flatten(p.Subprojects)...)
+	}
+
+	return all
+}
+
+// compareManifestFields compares the fields of two manifest projects that
+// are not part of the OBS project meta.
+func compareManifestFields(local, remote types.Project) []FieldChange {
+	var changes []FieldChange
+
+	fields := []struct {
+		name          string
+		local, remote string
+	}{
+		{"rootProject", local.RootProject, remote.RootProject},
+		{"apiURL", local.APIURL, remote.APIURL},
+		{"enabled", strconv.FormatBool(local.IsEnabled()), strconv.FormatBool(remote.IsEnabled())},
+		{"build", manifestBuildState(local.Build), manifestBuildState(remote.Build)},
+		{"publish", manifestPublishState(local.Publish), manifestPublishState(remote.Publish)},
+	}
+	for _, f := range fields {
+		if f.local != f.remote {
+			changes = append(changes, newChange(f.name, f.local, f.remote))
+		}
+	}
+
+	return append(changes, comparePackages(local.Packages, remote.Packages)...)
+}
+
+// manifestBuildState is empty when the manifest leaves the build flag
+// unmanaged.
+func manifestBuildState(build *obs.Build) string {
+	if build == nil {
+		return ""
+	}
+
+	return flagState(build.Disable)
+}
+
+// manifestPublishState is empty when the manifest leaves the publish flag
+// unmanaged.
+func manifestPublishState(publish *obs.Publish) string {
+	if publish == nil {
+		return ""
+	}
+
+	return flagState(publish.Disable)
+}
+
+func comparePackages(local, remote []obs.Package) []FieldChange {
+	var changes []FieldChange
+
+	l, r := packageKeys(local), packageKeys(remote)
+	if !slices.Equal(l, r) {
+		changes = append(changes, newChange("packages", strings.Join(l, ","), strings.Join(r, ",")))
+	}
+
+	remoteByName := make(map[string]obs.Package, len(remote))
+	for _, pkg := range remote {
+		remoteByName[pkg.Name] = pkg
+	}
+
+	for _, pkg := range local {
+		remotePkg, ok := remoteByName[pkg.Name]
+		if !ok {
+			continue
+		}
+
+		field := fmt.Sprintf("packages[%s]", pkg.Name)
+		fields := []struct {
+			name          string
+			local, remote string
+		}{
+			{"project", pkg.Project, remotePkg.Project},
+			{"title", pkg.Title, remotePkg.Title},
+			{"description", pkg.Description, remotePkg.Description},
+			{"devel", develKey(pkg.Devel), develKey(remotePkg.Devel)},
+		}
+		for _, f := range fields {
+			if f.local != f.remote {
+				changes = append(changes, newChange(field+"."+f.name, f.local, f.remote))
+			}
+		}
+	}
+
+	return changes
+}
+
+func packageKeys(packages []obs.Package) []string {
+	keys := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		keys = append(keys, pkg.Name)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+func develKey(devel *obs.Devel) string {
+	if devel == nil {
+		return ""
+	}
+
+	return devel.Project + "/" + devel.Package
+}
@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/obscli/types"
+	"sigs.k8s.io/release-sdk/obs"
+)
+
+func TestManifests(t *testing.T) {
+	manifest := func(projects ...types.Project) types.Projects {
+		return types.Projects{Projects: projects}
+	}
+	disabled := &obs.Build{Disable: &obs.Disabled{}}
+	kubeadm := obs.Package{Name: "kubeadm", Project: "isv:kubernetes"}
+
+	tests := []struct {
+		name         string
+		old, current types.Projects
+		want         []ProjectDiff
+	}{
+		{
+			name:    "equal",
+			old:     manifest(types.Project{Project: obs.Project{Name: "a", Build: disabled}}),
+			current: manifest(types.Project{Project: obs.Project{Name: "a", Build: disabled}}),
+			want:    []ProjectDiff{{Name: "a"}},
+		},
+		{
+			name:    "added and removed projects",
+			old:     manifest(types.Project{Project: obs.Project{Name: "a"}}),
+			current: manifest(types.Project{Project: obs.Project{Name: "b"}}),
+			want:    []ProjectDiff{{Name: "b", Missing: true}, {Name: "a", Removed: true}},
+		},
+		{
+			name: "subprojects are matched by name",
+			old: manifest(types.Project{Project: obs.Project{Name: "a"}, Subprojects: []types.Project{
+				{Project: obs.Project{Name: "a:sub", Title: "old"}},
+			}}),
+			current: manifest(types.Project{Project: obs.Project{Name: "a"}, Subprojects: []types.Project{
+				{Project: obs.Project{Name: "a:sub", Title: "new"}},
+			}}),
+			want: []ProjectDiff{
+				{Name: "a"},
+				{Name: "a:sub", Changes: []FieldChange{{Field: "title", Kind: ChangeModified, Local: "new", Remote: "old"}}},
+			},
+		},
+		{
+			name:    "manifest-only fields",
+			old:     manifest(types.Project{Project: obs.Project{Name: "a"}, RootProject: "r"}),
+			current: manifest(types.Project{Project: obs.Project{Name: "a"}, Packages: []obs.Package{kubeadm}}),
+			want: []ProjectDiff{{Name: "a", Changes: []FieldChange{
+				{Field: "rootProject", Kind: ChangeRemoved, Remote: "r"},
+				{Field: "packages", Kind: ChangeAdded, Local: "kubeadm"},
+			}}},
+		},
+		{
+			name:    "dropped build flag",
+			old:     manifest(types.Project{Project: obs.Project{Name: "a", Build: disabled}}),
+			current: manifest(types.Project{Project: obs.Project{Name: "a"}}),
+			want: []ProjectDiff{{Name: "a", Changes: []FieldChange{
+				{Field: "build", Kind: ChangeRemoved, Remote: "disabled"},
+			}}},
+		},
+		{
+			name:    "added publish flag",
+			old:     manifest(types.Project{Project: obs.Project{Name: "a"}}),
+			current: manifest(types.Project{Project: obs.Project{Name: "a", Publish: &obs.Publish{}}}),
+			want: []ProjectDiff{{Name: "a", Changes: []FieldChange{
+				{Field: "publish", Kind: ChangeAdded, Local: "enabled"},
+			}}},
+		},
+		{
+			name:    "modified build flag",
+			old:     manifest(types.Project{Project: obs.Project{Name: "a", Build: &obs.Build{}}}),
+			current: manifest(types.Project{Project: obs.Project{Name: "a", Build: disabled}}),
+			want: []ProjectDiff{{Name: "a", Changes: []FieldChange{
+				{Field: "build", Kind: ChangeModified, Local: "disabled", Remote: "enabled"},
+			}}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Manifests(tc.old, tc.current, Options{})
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Manifests() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
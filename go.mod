@@ -2,4 +2,7 @@ module sigs.k8s.io/obscli
 
 go 1.21.4
 
-require sigs.k8s.io/release-sdk v0.10.4
+require (
+	golang.org/x/text v0.14.0
+	sigs.k8s.io/release-sdk v0.10.4
+)
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"slices"
+
+	"sigs.k8s.io/release-sdk/obs"
+)
+
+// ResolveDefaults merges the manifest defaults into every project and
+// subproject, then clears them. Persons and repositories are only taken
+// from the defaults when a project declares none, and the default
+// architectures are used for repositories that list none.
+func (p *Projects) ResolveDefaults() {
+	if p.Defaults == nil {
+		return
+	}
+
+	for i := range p.Projects {
+		p.Defaults.apply(&p.Projects[i])
+	}
+	p.Defaults = nil
+}
+
+func (d *Defaults) apply(project *Project) {
+	if len(project.Persons) == 0 {
+		project.Persons = slices.Clone(d.Persons)
+	}
+
+	if len(project.Repositories) == 0 {
+		project.Repositories = make([]obs.Repository, 0, len(d.Repositories))
+		for _, repo := range d.Repositories {
+			repo.Architectures = slices.Clone(repo.Architectures)
+			repo.ReleaseTargets = slices.Clone(repo.ReleaseTargets)
+			repo.Paths = slices.Clone(repo.Paths)
+			project.Repositories = append(project.Repositories, repo)
+		}
+	}
+
+	for i := range project.Repositories {
+		if len(project.Repositories[i].Architectures) == 0 {
+			project.Repositories[i].Architectures = slices.Clone(d.Architectures)
+		}
+	}
+
+	for i := range project.Subprojects {
+		d.apply(&project.Subprojects[i])
+	}
+}
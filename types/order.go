@@ -0,0 +1,137 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortByDependencies reorders the manifest projects so that every project
+// comes after the projects its repository paths refer to, keeping the
+// manifest order otherwise. At the top level, references from subprojects
+// count towards their top-level project. The subprojects of each project
+// are then ordered the same way among their siblings, so dependencies
+// inside one project tree are respected too. References to projects
+// outside the manifest or within the same subtree are ignored at that
+// level. A dependency cycle is an error.
+func (p *Projects) SortByDependencies() error {
+	sorted, err := sortByDependencies(p.Projects)
+	if err != nil {
+		return err
+	}
+
+	p.Projects = sorted
+	return nil
+}
+
+func sortByDependencies(projects []Project) ([]Project, error) {
+	if len(projects) == 0 {
+		return projects, nil
+	}
+
+	owner := make(map[string]int)
+	for i := range projects {
+		for _, name := range projectNames(&projects[i]) {
+			owner[name] = i
+		}
+	}
+
+	deps := make([]map[int]bool, len(projects))
+	for i := range projects {
+		deps[i] = make(map[int]bool)
+		for _, ref := range pathReferences(&projects[i]) {
+			if j, ok := owner[ref]; ok && j != i {
+				deps[i][j] = true
+			}
+		}
+	}
+
+	sorted := make([]Project, 0, len(projects))
+	done := make([]bool, len(projects))
+	for len(sorted) < len(projects) {
+		progressed := false
+		for i := range projects {
+			if done[i] || !ready(deps[i], done) {
+				continue
+			}
+			sorted = append(sorted, projects[i])
+			done[i] = true
+			progressed = true
+		}
+
+		if !progressed {
+			var cycle []string
+			for i := range projects {
+				if reaches(deps, i, i) {
+					cycle = append(cycle, projects[i].Name)
+				}
+			}
+			return nil, fmt.Errorf("cyclic repository path dependencies between projects: %s", strings.Join(cycle, ", "))
+		}
+	}
+
+	for i := range sorted {
+		subprojects, err := sortByDependencies(sorted[i].Subprojects)
+		if err != nil {
+			return nil, err
+		}
+		sorted[i].Subprojects = subprojects
+	}
+
+	return sorted, nil
+}
+
+func ready(deps map[int]bool, done []bool) bool {
+	for j := range deps {
+		if !done[j] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reaches reports whether to can be reached from the dependencies of from.
+func reaches(deps []map[int]bool, from, to int) bool {
+	seen := make(map[int]bool)
+	queue := []int{from}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for j := range deps[i] {
+			if j == to {
+				return true
+			}
+			if !seen[j] {
+				seen[j] = true
+				queue = append(queue, j)
+			}
+		}
+	}
+
+	return false
+}
+
+func projectNames(p *Project) []string {
+	names := []string{p.Name}
+	for i := range p.Subprojects {
+		names = append(names, projectNames(&p.Subprojects[i])...)
+	}
+
+	return names
+}
+
+func pathReferences(p *Project) []string {
+	var refs []string
+	for _, repo := range p.Repositories {
+		for _, path := range repo.Paths {
+			refs = append(refs, path.Project)
+		}
+	}
+	for i := range p.Subprojects {
+		refs = append(refs, pathReferences(&p.Subprojects[i])...)
+	}
+	slices.Sort(refs)
+
+	return slices.Compact(refs)
+}
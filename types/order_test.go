@@ -0,0 +1,117 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/release-sdk/obs"
+)
+
+// node builds a project whose single repository has a path to each of deps.
+func node(name string, deps ...string) Project {
+	p := Project{Project: obs.Project{Name: name}}
+	if len(deps) > 0 {
+		repo := obs.Repository{Repository: "deb"}
+		for _, dep := range deps {
+			repo.Paths = append(repo.Paths, obs.RepositoryPath{Project: dep, Repository: "deb"})
+		}
+		p.Repositories = []obs.Repository{repo}
+	}
+
+	return p
+}
+
+func withSubprojects(p Project, subprojects ...Project) Project {
+	p.Subprojects = subprojects
+	return p
+}
+
+func names(projects []Project) string {
+	var all []string
+	for _, p := range projects {
+		all = append(all, p.Name)
+		if len(p.Subprojects) > 0 {
+			all = append(all, "("+names(p.Subprojects)+")")
+		}
+	}
+
+	return strings.Join(all, " ")
+}
+
+func TestSortByDependencies(t *testing.T) {
+	tests := []struct {
+		name     string
+		projects []Project
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "manifest order is kept without dependencies",
+			projects: []Project{node("b"), node("a"), node("c")},
+			want:     "b a c",
+		},
+		{
+			name:     "upstream before downstream",
+			projects: []Project{node("down", "mid"), node("mid", "up"), node("up")},
+			want:     "up mid down",
+		},
+		{
+			name:     "external and self references are ignored",
+			projects: []Project{node("a", "openSUSE:Factory", "a"), node("b")},
+			want:     "a b",
+		},
+		{
+			name: "dependency between subprojects of different trees",
+			projects: []Project{
+				withSubprojects(node("a"), node("a:sub", "b:sub")),
+				withSubprojects(node("b"), node("b:sub")),
+			},
+			want: "b (b:sub) a (a:sub)",
+		},
+		{
+			name: "siblings are ordered",
+			projects: []Project{
+				withSubprojects(node("k"), node("k:down", "k:up"), node("k:other"), node("k:up")),
+			},
+			want: "k (k:other k:up k:down)",
+		},
+		{
+			name: "nested subprojects are ordered",
+			projects: []Project{
+				withSubprojects(node("k"), withSubprojects(node("k:a"), node("k:a:down", "k:a:up"), node("k:a:up"))),
+			},
+			want: "k (k:a (k:a:up k:a:down))",
+		},
+		{
+			name:     "cycle names only the projects in the cycle",
+			projects: []Project{node("c", "a"), node("a", "b"), node("b", "a")},
+			wantErr:  "cyclic repository path dependencies between projects: a, b",
+		},
+		{
+			name: "cycle between siblings",
+			projects: []Project{
+				withSubprojects(node("k"), node("k:a", "k:b"), node("k:b", "k:a")),
+			},
+			wantErr: "cyclic repository path dependencies between projects: k:a, k:b",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := Projects{Projects: tc.projects}
+			err := p.SortByDependencies()
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("SortByDependencies() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SortByDependencies() error = %v", err)
+			}
+			if got := names(p.Projects); got != tc.want {
+				t.Errorf("SortByDependencies() order = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
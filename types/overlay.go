@@ -0,0 +1,76 @@
+package types
+
+// Overlay merges an environment overlay onto the manifest. Projects are
+// matched by name, recursively for subprojects. Every field the overlay
+// project sets replaces the base value, and lists such as persons or
+// repositories are replaced as a whole rather than merged. Projects that
+// only exist in the overlay are appended.
+func (p *Projects) Overlay(overlay *Projects) {
+	if overlay.APIURL != "" {
+		p.APIURL = overlay.APIURL
+	}
+	if overlay.Defaults != nil {
+		p.Defaults = overlay.Defaults
+	}
+
+	p.Projects = overlayProjects(p.Projects, overlay.Projects)
+}
+
+func overlayProjects(base, overlay []Project) []Project {
+	for i := range overlay {
+		found := false
+		for j := range base {
+			if base[j].Name == overlay[i].Name {
+				base[j].overlay(&overlay[i])
+				found = true
+				break
+			}
+		}
+		if !found {
+			base = append(base, overlay[i])
+		}
+	}
+
+	return base
+}
+
+func (p *Project) overlay(o *Project) {
+	setString := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	setString(&p.RootProject, o.RootProject)
+	setString(&p.APIURL, o.APIURL)
+	setString(&p.Kind, o.Kind)
+	setString(&p.Title, o.Title)
+	setString(&p.Description, o.Description)
+	setString(&p.URL, o.URL)
+
+	if o.Enabled != nil {
+		p.Enabled = o.Enabled
+	}
+	if o.Persons != nil {
+		p.Persons = o.Persons
+	}
+	if o.Repositories != nil {
+		p.Repositories = o.Repositories
+	}
+	if o.Build != nil {
+		p.Build = o.Build
+	}
+	if o.Publish != nil {
+		p.Publish = o.Publish
+	}
+	if o.DebugInfo != nil {
+		p.DebugInfo = o.DebugInfo
+	}
+	if o.UseForBuild != nil {
+		p.UseForBuild = o.UseForBuild
+	}
+	if o.Packages != nil {
+		p.Packages = o.Packages
+	}
+
+	p.Subprojects = overlayProjects(p.Subprojects, o.Subprojects)
+}
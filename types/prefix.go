@@ -0,0 +1,60 @@
+package types
+
+// AddPrefix rewrites the name of every project and subproject to
+// "<prefix>:<name>", e.g. to reconcile a manifest into a sandbox namespace.
+// References to manifest projects from root projects, packages, devel
+// projects, repository paths and release targets are rewritten too, so the
+// prefixed manifest stays self-consistent. References to projects outside
+// the manifest are left untouched.
+func (p *Projects) AddPrefix(prefix string) {
+	if prefix == "" {
+		return
+	}
+
+	names := make(map[string]bool)
+	collectNames(p.Projects, names)
+
+	rename := func(name string) string {
+		if !names[name] {
+			return name
+		}
+		return prefix + ":" + name
+	}
+
+	for i := range p.Projects {
+		p.Projects[i].rename(rename)
+	}
+}
+
+func collectNames(projects []Project, names map[string]bool) {
+	for i := range projects {
+		names[projects[i].Name] = true
+		collectNames(projects[i].Subprojects, names)
+	}
+}
+
+func (p *Project) rename(rename func(string) string) {
+	p.Name = rename(p.Name)
+	p.RootProject = rename(p.RootProject)
+
+	for i := range p.Packages {
+		p.Packages[i].Project = rename(p.Packages[i].Project)
+		if devel := p.Packages[i].Devel; devel != nil {
+			devel.Project = rename(devel.Project)
+		}
+	}
+
+	for i := range p.Repositories {
+		repo := &p.Repositories[i]
+		for j := range repo.Paths {
+			repo.Paths[j].Project = rename(repo.Paths[j].Project)
+		}
+		for j := range repo.ReleaseTargets {
+			repo.ReleaseTargets[j].ProjectName = rename(repo.ReleaseTargets[j].ProjectName)
+		}
+	}
+
+	for i := range p.Subprojects {
+		p.Subprojects[i].rename(rename)
+	}
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"cmp"
+	"slices"
+
+	"sigs.k8s.io/release-sdk/obs"
+)
+
+// Canonicalize sorts the persons, repositories, architectures and release
+// targets of every project and subproject, so the meta written to OBS is
+// the same on every run. Repository paths keep their order, as OBS resolves
+// them in sequence.
+func (p *Projects) Canonicalize() {
+	for i := range p.Projects {
+		p.Projects[i].Canonicalize()
+	}
+}
+
+// Canonicalize sorts the project and its subprojects like
+// Projects.Canonicalize.
+func (p *Project) Canonicalize() {
+	slices.SortFunc(p.Persons, func(a, b obs.Person) int {
+		if c := cmp.Compare(a.Role, b.Role); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+
+	slices.SortFunc(p.Repositories, func(a, b obs.Repository) int {
+		return cmp.Compare(a.Repository, b.Repository)
+	})
+	for i := range p.Repositories {
+		slices.Sort(p.Repositories[i].Architectures)
+		slices.SortFunc(p.Repositories[i].ReleaseTargets, compareReleaseTargets)
+	}
+
+	for i := range p.Subprojects {
+		p.Subprojects[i].Canonicalize()
+	}
+}
+
+func compareReleaseTargets(a, b obs.ReleaseTarget) int {
+	if c := cmp.Compare(a.ProjectName, b.ProjectName); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Repository, b.Repository); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Trigger, b.Trigger)
+}
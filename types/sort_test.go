@@ -0,0 +1,51 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/release-sdk/obs"
+)
+
+func TestCanonicalize(t *testing.T) {
+	alice := obs.Person{UserID: "alice", Role: obs.PersonRoleMaintainer}
+	bob := obs.Person{UserID: "bob", Role: obs.PersonRoleMaintainer}
+	carol := obs.Person{UserID: "carol", Role: obs.PersonRoleBugOwner}
+	release := obs.ReleaseTarget{ProjectName: "isv:kubernetes:release", Repository: "deb", Trigger: "manual"}
+	staging := obs.ReleaseTarget{ProjectName: "isv:kubernetes:staging", Repository: "deb", Trigger: "manual"}
+	factory := obs.RepositoryPath{Project: "openSUSE:Factory", Repository: "snapshot"}
+	debian := obs.RepositoryPath{Project: "Debian:12", Repository: "standard"}
+
+	project := func(persons []obs.Person, repos []obs.Repository) Project {
+		return Project{Project: obs.Project{Name: "isv:kubernetes", Persons: persons, Repositories: repos}}
+	}
+
+	p := Projects{Projects: []Project{
+		project([]obs.Person{bob, carol, alice}, []obs.Repository{
+			{Repository: "rpm", Architectures: []string{"x86_64", "aarch64"}},
+			{
+				Repository:     "deb",
+				Architectures:  []string{"s390x", "ppc64le"},
+				ReleaseTargets: []obs.ReleaseTarget{staging, release},
+				Paths:          []obs.RepositoryPath{factory, debian},
+			},
+		}),
+	}}
+	p.Projects[0].Subprojects = []Project{project([]obs.Person{bob, alice}, nil)}
+
+	want := project([]obs.Person{carol, alice, bob}, []obs.Repository{
+		{
+			Repository:     "deb",
+			Architectures:  []string{"ppc64le", "s390x"},
+			ReleaseTargets: []obs.ReleaseTarget{release, staging},
+			Paths:          []obs.RepositoryPath{factory, debian},
+		},
+		{Repository: "rpm", Architectures: []string{"aarch64", "x86_64"}},
+	})
+	want.Subprojects = []Project{project([]obs.Person{alice, bob}, nil)}
+
+	p.Canonicalize()
+	if !reflect.DeepEqual(p.Projects[0], want) {
+		t.Errorf("Canonicalize() = %+v, want %+v", p.Projects[0], want)
+	}
+}
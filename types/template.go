@@ -0,0 +1,57 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// RenderTemplates expands Go templates in the title and description of
+// every project and subproject, using the project itself as data, e.g.
+// "Kubernetes release packages for {{.Name}}". Literal text is left as is.
+func (p *Projects) RenderTemplates() error {
+	for i := range p.Projects {
+		if err := p.Projects[i].renderTemplates(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Project) renderTemplates() error {
+	var err error
+
+	if p.Title, err = p.render("title", p.Title); err != nil {
+		return err
+	}
+	if p.Description, err = p.render("description", p.Description); err != nil {
+		return err
+	}
+
+	for i := range p.Subprojects {
+		if err := p.Subprojects[i].renderTemplates(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Project) render(field, text string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New(field).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("project %s: parsing %s template: %w", p.Name, field, err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, p); err != nil {
+		return "", fmt.Errorf("project %s: rendering %s template: %w", p.Name, field, err)
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/release-sdk/obs"
+)
+
+func TestRenderTemplates(t *testing.T) {
+	tests := []struct {
+		name            string
+		project         Project
+		wantTitle       string
+		wantDescription string
+		wantErr         string
+	}{
+		{
+			name:      "literal title",
+			project:   Project{Project: obs.Project{Name: "isv:kubernetes", Title: "Kubernetes"}},
+			wantTitle: "Kubernetes",
+		},
+		{
+			name: "name template",
+			project: Project{Project: obs.Project{
+				Name:        "isv:kubernetes:core",
+				Title:       "Kubernetes release packages for {{.Name}}",
+				Description: "Packages built from {{.RootProject}}",
+			}, RootProject: "isv:kubernetes"},
+			wantTitle:       "Kubernetes release packages for isv:kubernetes:core",
+			wantDescription: "Packages built from isv:kubernetes",
+		},
+		{
+			name: "subprojects are rendered",
+			project: Project{Project: obs.Project{Name: "isv:kubernetes"}, Subprojects: []Project{
+				{Project: obs.Project{Name: "isv:kubernetes:sub", Title: "{{.Name}}"}},
+			}},
+		},
+		{
+			name:    "parse error names the project",
+			project: Project{Project: obs.Project{Name: "isv:kubernetes", Title: "{{.Name"}},
+			wantErr: "project isv:kubernetes: parsing title template",
+		},
+		{
+			name:    "execution error names the project",
+			project: Project{Project: obs.Project{Name: "isv:kubernetes", Description: "{{.Missing}}"}},
+			wantErr: "project isv:kubernetes: rendering description template",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := Projects{Projects: []Project{tc.project}}
+			err := p.RenderTemplates()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("RenderTemplates() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderTemplates() error = %v", err)
+			}
+
+			got := p.Projects[0]
+			if got.Title != tc.wantTitle {
+				t.Errorf("title = %q, want %q", got.Title, tc.wantTitle)
+			}
+			if got.Description != tc.wantDescription {
+				t.Errorf("description = %q, want %q", got.Description, tc.wantDescription)
+			}
+			for _, sub := range got.Subprojects {
+				if sub.Title != sub.Name {
+					t.Errorf("subproject title = %q, want %q", sub.Title, sub.Name)
+				}
+			}
+		})
+	}
+}
@@ -5,12 +5,45 @@ import (
 )
 
 type Projects struct {
+	APIURL   string    `json:"apiURL,omitempty"`
+	Defaults *Defaults `json:"defaults,omitempty"`
 	Projects []Project `json:"projects"`
 }
 
+// Defaults are merged into every project that does not set the field itself.
+type Defaults struct {
+	Persons       []obs.Person     `json:"persons,omitempty"`
+	Repositories  []obs.Repository `json:"repositories,omitempty"`
+	Architectures []string         `json:"architectures,omitempty"`
+}
+
 type Project struct {
 	obs.Project
 	RootProject string        `json:"rootProject,omitempty"`
+	APIURL      string        `json:"apiURL,omitempty"`
+	Enabled     *bool         `json:"enabled,omitempty"`
 	Packages    []obs.Package `json:"packages,omitempty"`
 	Subprojects []Project     `json:"subprojects,omitempty"`
 }
+
+// IsEnabled reports whether the project should be reconciled. Projects are
+// enabled unless the manifest sets enabled to false.
+func (p *Project) IsEnabled() bool {
+	return p.Enabled == nil || *p.Enabled
+}
+
+// APIURLFor returns the OBS API URL to use for project. The project's own
+// apiURL takes precedence, then override (an explicitly set --api-url),
+// then the manifest apiURL, and finally fallback.
+func (p *Projects) APIURLFor(project *Project, override, fallback string) string {
+	switch {
+	case project.APIURL != "":
+		return project.APIURL
+	case override != "":
+		return override
+	case p.APIURL != "":
+		return p.APIURL
+	default:
+		return fallback
+	}
+}
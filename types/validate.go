@@ -0,0 +1,99 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"unicode/utf8"
+)
+
+// ProjectKinds are the project kinds accepted by OBS. An empty kind is
+// treated as standard.
+var ProjectKinds = []string{
+	"standard",
+	"maintenance",
+	"maintenance_incident",
+	"maintenance_release",
+}
+
+// Limits bounds the length of project text fields and restricts which
+// architectures may share a repository. Zero lengths mean unlimited.
+type Limits struct {
+	// MaxTitleLength is counted in characters.
+	MaxTitleLength int
+	// MaxDescriptionLength is counted in UTF-8 encoded bytes.
+	MaxDescriptionLength int
+	// ForbiddenArchitectures lists combinations of architectures that must
+	// not all be enabled in the same repository.
+	ForbiddenArchitectures [][]string
+}
+
+// DefaultLimits match the columns OBS stores titles and descriptions in: a
+// string of 255 characters and a text column of 65535 bytes.
+var DefaultLimits = Limits{
+	MaxTitleLength:       255,
+	MaxDescriptionLength: 65535,
+}
+
+// Validate checks every project and subproject in the manifest against
+// DefaultLimits and returns all problems found.
+func (p *Projects) Validate() error {
+	return p.ValidateWithLimits(DefaultLimits)
+}
+
+// ValidateWithLimits is like Validate but checks text lengths and
+// repository architectures against limits.
+func (p *Projects) ValidateWithLimits(limits Limits) error {
+	var errs []error
+	for i := range p.Projects {
+		errs = append(errs, p.Projects[i].validate(limits)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *Project) validate(limits Limits) []error {
+	var errs []error
+
+	if p.Kind != "" && !slices.Contains(ProjectKinds, p.Kind) {
+		errs = append(errs, fmt.Errorf("project %s: invalid kind %q, must be one of %v", p.Name, p.Kind, ProjectKinds))
+	}
+
+	if n := utf8.RuneCountInString(p.Title); limits.MaxTitleLength > 0 && n > limits.MaxTitleLength {
+		errs = append(errs, fmt.Errorf("project %s: title is %d characters long, maximum is %d", p.Name, n, limits.MaxTitleLength))
+	}
+	if n := len(p.Description); limits.MaxDescriptionLength > 0 && n > limits.MaxDescriptionLength {
+		errs = append(errs, fmt.Errorf("project %s: description is %d bytes long, maximum is %d bytes", p.Name, n, limits.MaxDescriptionLength))
+	}
+
+	for _, repo := range p.Repositories {
+		for _, forbidden := range limits.ForbiddenArchitectures {
+			if len(forbidden) > 0 && containsAll(repo.Architectures, forbidden) {
+				errs = append(errs, fmt.Errorf("project %s: repository %s: architectures %v must not be combined", p.Name, repo.Repository, forbidden))
+			}
+		}
+	}
+
+	if p.URL != "" {
+		if u, err := url.Parse(p.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("project %s: url %q must be an absolute URL with scheme and host", p.Name, p.URL))
+		}
+	}
+
+	for i := range p.Subprojects {
+		errs = append(errs, p.Subprojects[i].validate(limits)...)
+	}
+
+	return errs
+}
+
+func containsAll(architectures, wanted []string) bool {
+	for _, arch := range wanted {
+		if !slices.Contains(architectures, arch) {
+			return false
+		}
+	}
+
+	return true
+}
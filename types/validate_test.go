@@ -0,0 +1,82 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/release-sdk/obs"
+)
+
+func TestValidateWithLimits(t *testing.T) {
+	limits := Limits{
+		MaxTitleLength:         4,
+		MaxDescriptionLength:   6,
+		ForbiddenArchitectures: [][]string{{"i586", "riscv64"}},
+	}
+
+	tests := []struct {
+		name    string
+		project obs.Project
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			project: obs.Project{Name: "a", Kind: "maintenance", Title: "café", Description: "abcdef", URL: "https://kubernetes.io"},
+		},
+		{
+			name:    "invalid kind",
+			project: obs.Project{Name: "a", Kind: "standrad"},
+			wantErr: `project a: invalid kind "standrad"`,
+		},
+		{
+			name:    "title too long",
+			project: obs.Project{Name: "a", Title: "cafés"},
+			wantErr: "project a: title is 5 characters long",
+		},
+		{
+			name:    "description at the byte limit",
+			project: obs.Project{Name: "a", Description: "日本"},
+		},
+		{
+			name:    "description too long",
+			project: obs.Project{Name: "a", Description: "日本語"},
+			wantErr: "project a: description is 9 bytes long, maximum is 6 bytes",
+		},
+		{
+			name:    "relative url",
+			project: obs.Project{Name: "a", URL: "kubernetes.io"},
+			wantErr: `project a: url "kubernetes.io" must be an absolute URL`,
+		},
+		{
+			name: "forbidden architectures",
+			project: obs.Project{Name: "a", Repositories: []obs.Repository{
+				{Repository: "deb", Architectures: []string{"x86_64", "i586", "riscv64"}},
+			}},
+			wantErr: "project a: repository deb: architectures [i586 riscv64] must not be combined",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := Projects{Projects: []Project{{Project: tc.project}}}
+			err := p.ValidateWithLimits(limits)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateWithLimits() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("ValidateWithLimits() error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDefaultDescriptionLimit(t *testing.T) {
+	// 30000 CJK characters take 90000 bytes, more than the OBS text column.
+	p := Projects{Projects: []Project{{Project: obs.Project{Name: "a", Description: strings.Repeat("日", 30000)}}}}
+	if err := p.Validate(); err == nil || !strings.Contains(err.Error(), "description is 90000 bytes long") {
+		t.Errorf("Validate() error = %v, want a description length error", err)
+	}
+}